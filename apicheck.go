@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// -check protects downstream packages from silent breakage: regenerating a
+// file because someone tweaked an unrelated lina tag shouldn't quietly
+// delete a previously-public accessor. Modeled on cmd/api/goapi.go's
+// exported-API diff.
+var (
+	checkMode  = flag.Bool("check", false, "compare the about-to-be-written file's exported API against what's on disk; fail the run on removal or signature change")
+	allowBreak = flag.Bool("break", false, "with -check, report breaking changes instead of failing the run")
+	allowNew   = flag.Bool("allow-new", true, "with -check, allow newly added exported methods (set false to require every method be pre-declared)")
+)
+
+// exportedMethod is one directly-declared, exported method found on a
+// target type, normalized enough to compare across runs.
+type exportedMethod struct {
+	Name      string
+	Signature string // params + results, whitespace-normalized
+}
+
+// apiBreak is one way the about-to-be-written API differs from what's
+// already on disk.
+type apiBreak struct {
+	Method string
+	Reason string
+}
+
+// checkCompat runs the -check comparison for stName's about-to-be-written
+// src (the exact bytes about to be written to its output file), failing
+// the run unless -break is set.
+func (g *Generator) checkCompat(stName string, src []byte) {
+	if !*checkMode {
+		return
+	}
+	oldMethods := g.collectExistingMethods(stName)
+	newMethods, err := g.collectGeneratedMethods(stName, src)
+	if err != nil {
+		log.Fatal(err)
+	}
+	breaks := diffMethods(oldMethods, newMethods, *allowNew)
+	if len(breaks) == 0 {
+		return
+	}
+	for _, b := range breaks {
+		log.Printf("API check: %s.%s %s", stName, b.Method, b.Reason)
+	}
+	if !*allowBreak {
+		log.Fatalf("API check failed for %q: %d breaking change(s); rerun with -break to override", stName, len(breaks))
+	}
+}
+
+// collectExistingMethods scans stName's output file as the already-loaded
+// package saw it - which, before this run overwrites anything, still
+// reflects whatever is on disk - for every exported method declared
+// directly on stName. Methods declared in the package's other files are
+// not collected.
+func (g *Generator) collectExistingMethods(stName string) map[string]exportedMethod {
+	out := make(map[string]exportedMethod)
+	if g.pkg == nil {
+		return out
+	}
+	outputFile := strings.ToLower(stName) + "_lina.go"
+	if tc, ok := g.typeConfigFor(stName); ok {
+		outputFile = tc.outputFile()
+	}
+	for _, file := range g.pkg.files {
+		if filepath.Base(file.fileSet.Position(file.file.Package).Filename) != filepath.Base(outputFile) {
+			continue
+		}
+		for _, decl := range file.file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !isExportedMethodOf(fn, stName) {
+				continue
+			}
+			out[fn.Name.Name] = exportedMethod{
+				Name:      fn.Name.Name,
+				Signature: g.methodSignature(fn),
+			}
+		}
+	}
+	return out
+}
+
+// collectGeneratedMethods parses the buffer lina is about to write for
+// stName and extracts the same shape of information. The code hasn't been
+// written to disk or type-checked yet, so this works purely from the AST.
+func (g *Generator) collectGeneratedMethods(stName string, src []byte) (map[string]exportedMethod, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, strings.ToLower(stName)+"_lina.go", src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parsing generated code for %q: %w", stName, err)
+	}
+	out := make(map[string]exportedMethod)
+	for _, decl := range f.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || !isExportedMethodOf(fn, stName) {
+			continue
+		}
+		out[fn.Name.Name] = exportedMethod{
+			Name:      fn.Name.Name,
+			Signature: normalizeSignature(printedType(fn.Type)),
+		}
+	}
+	return out, nil
+}
+
+func isExportedMethodOf(fn *ast.FuncDecl, stName string) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return false
+	}
+	return receiverTypeName(fn.Recv.List[0].Type) == stName && token.IsExported(fn.Name.Name)
+}
+
+func receiverTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// methodSignature renders a FuncDecl's parameter and result types, using
+// the type-checked go/types signature when available (so a renamed alias
+// still compares equal) and falling back to the printed AST otherwise.
+func (g *Generator) methodSignature(fn *ast.FuncDecl) string {
+	if g.pkg != nil && g.pkg.info != nil {
+		if obj, ok := g.pkg.info.Defs[fn.Name]; ok && obj != nil {
+			if sig, isSig := obj.Type().(*types.Signature); isSig {
+				return normalizeSignature(types.TypeString(sig, types.RelativeTo(g.pkg.types)))
+			}
+		}
+	}
+	return normalizeSignature(printedType(fn.Type))
+}
+
+func printedType(t *ast.FuncType) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, token.NewFileSet(), t); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// normalizeSignature collapses whitespace so a comment- or formatting-only
+// regeneration never trips -check.
+func normalizeSignature(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// diffMethods reports every breaking difference between the method set
+// already on disk (old) and the one about to be written (new): a removed
+// method, or one whose signature changed. New methods are only reported
+// when allowNew is false.
+func diffMethods(old, new map[string]exportedMethod, allowNew bool) []apiBreak {
+	var breaks []apiBreak
+	for name, oldM := range old {
+		newM, stillPresent := new[name]
+		switch {
+		case !stillPresent:
+			breaks = append(breaks, apiBreak{Method: name, Reason: "removed"})
+		case newM.Signature != oldM.Signature:
+			breaks = append(breaks, apiBreak{Method: name, Reason: fmt.Sprintf("signature changed: %s -> %s", oldM.Signature, newM.Signature)})
+		}
+	}
+	if !allowNew {
+		for name := range new {
+			if _, existed := old[name]; !existed {
+				breaks = append(breaks, apiBreak{Method: name, Reason: "added"})
+			}
+		}
+	}
+	return breaks
+}
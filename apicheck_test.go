@@ -0,0 +1,144 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffMethods(t *testing.T) {
+	existing := map[string]exportedMethod{
+		"Foo": {Name: "Foo", Signature: "func (c *Config) Foo() string"},
+		"Bar": {Name: "Bar", Signature: "func (c *Config) Bar() int"},
+	}
+
+	cases := []struct {
+		name     string
+		old      map[string]exportedMethod
+		new      map[string]exportedMethod
+		allowNew bool
+		want     []apiBreak
+	}{
+		{
+			name: "new method added is ok when allowNew",
+			old:  existing,
+			new: map[string]exportedMethod{
+				"Foo":  existing["Foo"],
+				"Bar":  existing["Bar"],
+				"Quux": {Name: "Quux", Signature: "func (c *Config) Quux() bool"},
+			},
+			allowNew: true,
+			want:     nil,
+		},
+		{
+			name: "new method added fails when allowNew is false",
+			old:  existing,
+			new: map[string]exportedMethod{
+				"Foo":  existing["Foo"],
+				"Bar":  existing["Bar"],
+				"Quux": {Name: "Quux", Signature: "func (c *Config) Quux() bool"},
+			},
+			allowNew: false,
+			want:     []apiBreak{{Method: "Quux", Reason: "added"}},
+		},
+		{
+			name: "method removed fails",
+			// Also covers a field flipping from unexported (had an
+			// accessor) to exported (accessor goes away): at the
+			// diffMethods level that's indistinguishable from any
+			// other removed method.
+			old: existing,
+			new: map[string]exportedMethod{
+				"Foo": existing["Foo"],
+			},
+			allowNew: true,
+			want:     []apiBreak{{Method: "Bar", Reason: "removed"}},
+		},
+		{
+			name: "return type changed fails",
+			old:  existing,
+			new: map[string]exportedMethod{
+				"Foo": {Name: "Foo", Signature: "func (c *Config) Foo() int"},
+				"Bar": existing["Bar"],
+			},
+			allowNew: true,
+			want: []apiBreak{{
+				Method: "Foo",
+				Reason: "signature changed: func (c *Config) Foo() string -> func (c *Config) Foo() int",
+			}},
+		},
+		{
+			name: "whitespace-only diff is ok",
+			old:  existing,
+			new: map[string]exportedMethod{
+				"Foo": {Name: "Foo", Signature: normalizeSignature("func  (c *Config)   Foo()   string")},
+				"Bar": {Name: "Bar", Signature: normalizeSignature("func (c *Config)\nBar() int")},
+			},
+			allowNew: true,
+			want:     nil,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := diffMethods(tc.old, tc.new, tc.allowNew)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("diffMethods() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeSignature(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"func (c *Config) Foo() string", "func (c *Config) Foo() string"},
+		{"func (c *Config)   Foo()   string", "func (c *Config) Foo() string"},
+		{"func (c *Config) Foo() string // a trailing comment", "func (c *Config) Foo() string // a trailing comment"},
+		{"func (c *Config) Foo(\n\tctx context.Context,\n) string", "func (c *Config) Foo( ctx context.Context, ) string"},
+	}
+	for _, tc := range cases {
+		if got := normalizeSignature(tc.in); got != tc.want {
+			t.Errorf("normalizeSignature(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestCollectGeneratedMethods_CommentAndFormattingOnlyDiff exercises the
+// actual parsing path -check uses: a regenerated file with an added doc
+// comment and reflowed whitespace, but the same exported methods, must not
+// diff as a break.
+func TestCollectGeneratedMethods_CommentAndFormattingOnlyDiff(t *testing.T) {
+	g := &Generator{}
+
+	before := []byte(`package config
+
+func (c *Config) Foo() string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.foo
+}
+`)
+	after := []byte(`package config
+
+// Foo returns the current value of foo.
+func (c  *Config)  Foo()   string {
+	c.RLock()
+	defer c.RUnlock()
+	return c.foo
+}
+`)
+
+	oldMethods, err := g.collectGeneratedMethods("Config", before)
+	if err != nil {
+		t.Fatalf("collectGeneratedMethods(before): %s", err)
+	}
+	newMethods, err := g.collectGeneratedMethods("Config", after)
+	if err != nil {
+		t.Fatalf("collectGeneratedMethods(after): %s", err)
+	}
+
+	if breaks := diffMethods(oldMethods, newMethods, true); len(breaks) != 0 {
+		t.Errorf("diffMethods() = %#v, want no breaks for a comment-only diff", breaks)
+	}
+}
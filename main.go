@@ -27,26 +27,84 @@ const (
 )
 
 var (
-	typeNames  = flag.String("type", "Config", "comma-separated list of type names; must be set")
-	output     = ""
-	dir        = "."
+	configPath = flag.String("config", "lina.yaml", "path to the lina config file")
 	getterTmpl = "getter"
 	setterTmpl = "setter"
-	tmpl       = map[string]string{
+	// defaultTemplates are used for any template name not overridden by the
+	// loaded Config's Templates section.
+	defaultTemplates = map[string]string{
 		"setter": `func ({{.Receiver}} *{{.Struct}}) DoNotUseThisToSet{{.UpperField}}({{.Field}} {{.Type}}) {
+	{{.Receiver}}{{.Lock}}.Lock()
+	defer {{.Receiver}}{{.Lock}}.Unlock()
 	{{.Receiver}}.{{.Field}} = {{.Field}}
 }
 `,
 		"getter": `func ({{.Receiver}} *{{.Struct}}) {{.UpperField}}() {{.Type}} {
-	{{.Receiver}}.RLock()
-	defer {{.Receiver}}.RUnlock()
+	{{.Receiver}}{{.Lock}}.{{.RLockMethod}}()
+	defer {{.Receiver}}{{.Lock}}.{{.RUnlockMethod}}()
 	return {{.Receiver}}.{{.Field}}
 }
 `,
+		"getter_atomic": `func ({{.Receiver}} *{{.Struct}}) {{.UpperField}}() {{.Type}} {
+	return {{.AtomicLoad}}
+}
+`,
+		"setter_atomic": `func ({{.Receiver}} *{{.Struct}}) DoNotUseThisToSet{{.UpperField}}({{.Field}} {{.Type}}) {
+	{{.AtomicStore}}
+}
+`,
+		"getter_copy": `func ({{.Receiver}} *{{.Struct}}) {{.UpperField}}() {{.Type}} {
+	{{.Receiver}}{{.Lock}}.{{.RLockMethod}}()
+	defer {{.Receiver}}{{.Lock}}.{{.RUnlockMethod}}()
+	{{.CopyBody}}
+}
+`,
+		"getter_try": `func ({{.Receiver}} *{{.Struct}}) {{.UpperField}}() ({{.Type}}, bool) {
+	if !{{.Receiver}}{{.Lock}}.{{.TryRLockMethod}}() {
+		var zero {{.Type}}
+		return zero, false
+	}
+	defer {{.Receiver}}{{.Lock}}.{{.RUnlockMethod}}()
+	return {{.Receiver}}.{{.Field}}, true
+}
+`,
+		"setter_try": `func ({{.Receiver}} *{{.Struct}}) DoNotUseThisToSet{{.UpperField}}({{.Field}} {{.Type}}) bool {
+	if !{{.Receiver}}{{.Lock}}.TryLock() {
+		return false
+	}
+	defer {{.Receiver}}{{.Lock}}.Unlock()
+	{{.Receiver}}.{{.Field}} = {{.Field}}
+	return true
+}
+`,
+		"getter_ctx": `func ({{.Receiver}} *{{.Struct}}) {{.UpperField}}(ctx context.Context) ({{.Type}}, error) {
+	for !{{.Receiver}}{{.Lock}}.{{.TryRLockMethod}}() {
+		select {
+		case <-ctx.Done():
+			var zero {{.Type}}
+			return zero, ctx.Err()
+		case <-time.After(linaLockPollBackoff):
+		}
+	}
+	defer {{.Receiver}}{{.Lock}}.{{.RUnlockMethod}}()
+	return {{.Receiver}}.{{.Field}}, nil
+}
+`,
+		"setter_ctx": `func ({{.Receiver}} *{{.Struct}}) DoNotUseThisToSet{{.UpperField}}(ctx context.Context, {{.Field}} {{.Type}}) error {
+	for !{{.Receiver}}{{.Lock}}.TryLock() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(linaLockPollBackoff):
+		}
 	}
-	typeMap = map[string]bool{
-		"Config": true,
+	defer {{.Receiver}}{{.Lock}}.Unlock()
+	{{.Receiver}}.{{.Field}} = {{.Field}}
+	return nil
+}
+`,
 	}
+	typeMap = map[string]bool{}
 )
 
 var (
@@ -66,54 +124,62 @@ func main() {
 	flag.Usage = Usage
 	flag.Parse()
 
-	if len(*typeNames) == 0 {
-		flag.Usage()
-		os.Exit(1)
+	cfg, err := LoadConfig(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, p := range plugins {
+		if err := p.MutateConfig(cfg); err != nil {
+			log.Fatalf("plugin %q: %s", p.Name(), err)
+		}
 	}
 
-	types := strings.Split(*typeNames, ",")
-	for _, typeName := range types {
-		typeMap[typeName] = true
+	for _, tc := range cfg.Types {
+		typeMap[tc.Name] = true
 	}
-	// We accept either one directory or a list of files. Which do we have?
-	args := flag.Args()
+
+	// We accept either one directory or a list of files, same as the old
+	// positional args; it now comes from the config's dir field.
+	args := strings.Fields(cfg.Dir)
 	if len(args) == 0 {
-		// Default: process whole package in current directory.
 		args = []string{"."}
 	}
-	dir = args[0]
-	// Parse the package once.
-	// var dir string
+
 	g := Generator{
+		cfg:      cfg,
 		buf:      make(map[string]*bytes.Buffer),
 		walkMark: make(map[string]bool),
 	}
 
 	g.parsePackage(args)
 
-	// Print the header and package clause.
-	// Run generate for each type.
-	for i, typeName := range types {
-		g.generate(typeName)
-		// AccessWrite to file.
-		if output == "" {
-			output = fmt.Sprintf("%s_lina.go", types[i])
+	// Run generate for each configured type and write its output file.
+	for _, tc := range cfg.Types {
+		g.generate(tc.Name)
+		outputName := filepath.Join(args[0], tc.outputFile())
+		buf, ok := g.buf[tc.Name]
+		if !ok {
+			log.Fatalf("type %q: no matching struct found in %s", tc.Name, args[0])
 		}
-		outputName := filepath.Join(dir, strings.ToLower(output))
-		buf := g.buf[typeName]
-		var src = (buf).Bytes()
 
-		err := os.WriteFile(outputName, src, 0644)
-		if err != nil {
+		g.checkCompat(tc.Name, buf.Bytes())
+
+		if err := os.WriteFile(outputName, buf.Bytes(), 0644); err != nil {
 			log.Fatalf("writing output: %s", err)
 		}
 	}
 
+	for _, p := range plugins {
+		if err := p.GenerateCode(&g); err != nil {
+			log.Fatalf("plugin %q: %s", p.Name(), err)
+		}
+	}
 }
 
 // Generator holds the state of the analysis. Primarily used to buffer
 // the output for format.Source.
 type Generator struct {
+	cfg      *Config                  // Loaded lina.yaml; nil only in tests that bypass main.
 	buf      map[string]*bytes.Buffer // Accumulated output.
 	pkg      *Package                 // Package we are scanning.
 	walkMark map[string]bool
@@ -141,6 +207,8 @@ type File struct {
 type Package struct {
 	name  string
 	defs  map[*ast.Ident]types.Object
+	info  *types.Info
+	types *types.Package
 	files []*File
 }
 
@@ -159,8 +227,8 @@ func (g *Generator) parsePackage(patterns []string) {
 	if len(pkgs) != 1 {
 		log.Fatalf("error: %d packages found", len(pkgs))
 	}
-	if pkgs[0].Name != "config" {
-		log.Fatalf("package name must be %q, cur package %q", "config", pkgs[0].Name)
+	if pkgs[0].Name != g.cfg.Package {
+		log.Fatalf("package name must be %q, cur package %q", g.cfg.Package, pkgs[0].Name)
 	}
 	g.addPackage(pkgs[0])
 }
@@ -170,6 +238,8 @@ func (g *Generator) addPackage(pkg *packages.Package) {
 	g.pkg = &Package{
 		name:  pkg.Name,
 		defs:  pkg.TypesInfo.Defs,
+		info:  pkg.TypesInfo,
+		types: pkg.Types,
 		files: make([]*File, len(pkg.Syntax)),
 	}
 
@@ -183,19 +253,159 @@ func (g *Generator) addPackage(pkg *packages.Package) {
 }
 
 var structInfos []map[string][]StructFieldInfo
+var structLocks = make(map[string][]LockInfo)
 
 func checkExported(string, []StructFieldInfo) {
 
 }
 
+// LockInfo describes one embedded sync.Mutex/sync.RWMutex found on a struct.
+// Name is empty for an anonymously embedded lock, whose RLock/Lock methods
+// are promoted straight onto the struct.
+type LockInfo struct {
+	Name string
+	Kind string // "Mutex" or "RWMutex"
+}
+
+// lockSelector resolves the tag's requested lock group (if any) against the
+// locks collected for stName, falling back to the sole lock when a struct
+// only declares one, or to defaultLock (from the type's config entry) when
+// it declares several. It returns the receiver-relative selector to splice
+// in front of Lock/Unlock/RLock/RUnlock, e.g. "" or ".cfgLock", along with
+// the resolved lock's Kind ("Mutex" or "RWMutex").
+func lockSelector(stName string, field StructFieldInfo, defaultLock string) (selector, kind string) {
+	locks := structLocks[stName]
+	want := field.Lock
+	if want == "" {
+		want = defaultLock
+	}
+	if want != "" {
+		for _, l := range locks {
+			if l.Name == want {
+				return "." + l.Name, l.Kind
+			}
+		}
+		log.Fatalf("field %q in struct %q requests lock %q, but no such embedded lock exists", field.Name, stName, want)
+	}
+	switch len(locks) {
+	case 0:
+		log.Fatalf("struct %q has no embedded sync.Mutex/sync.RWMutex for field %q", stName, field.Name)
+	case 1:
+		if locks[0].Name == "" {
+			return "", locks[0].Kind
+		}
+		return "." + locks[0].Name, locks[0].Kind
+	default:
+		// No explicit request, but one of the several locks is the
+		// anonymously embedded one - that's the lock every field used
+		// before named lock groups existed, so it stays the default
+		// for any field that doesn't ask for a named group.
+		for _, l := range locks {
+			if l.Name == "" {
+				return "", l.Kind
+			}
+		}
+		log.Fatalf("field %q in struct %q must set lina:\"...,lock=name\" or the type's config lock: struct has multiple named lock groups and no anonymous default", field.Name, stName)
+	}
+	return "", ""
+}
+
+// readLockMethods returns the method names a read accessor should call for a
+// lock of the given Kind: a plain sync.Mutex has no RLock/RUnlock/TryRLock,
+// so reads share its writer-side Lock/Unlock/TryLock instead.
+func readLockMethods(kind string) (lock, unlock, tryLock string) {
+	if kind == "Mutex" {
+		return "Lock", "Unlock", "TryLock"
+	}
+	return "RLock", "RUnlock", "TryRLock"
+}
+
+// typeConfigFor finds stName's entry in the loaded Config, if any.
+func (g *Generator) typeConfigFor(stName string) (TypeConfig, bool) {
+	if g.cfg == nil {
+		return TypeConfig{}, false
+	}
+	for _, tc := range g.cfg.Types {
+		if tc.Name == stName {
+			return tc, true
+		}
+	}
+	return TypeConfig{}, false
+}
+
+// tagFor resolves the lina tag name for a type: its own Tag override, else
+// Config.Tag, else the TagName constant.
+func (g *Generator) tagFor(stName string) string {
+	if tc, ok := g.typeConfigFor(stName); ok && tc.Tag != "" {
+		return tc.Tag
+	}
+	if g.cfg != nil && g.cfg.Tag != "" {
+		return g.cfg.Tag
+	}
+	return TagName
+}
+
+// receiverFor resolves the receiver name for a type: its own Receiver
+// override, else the lowercased first letter of its name.
+func (g *Generator) receiverFor(stName string) string {
+	if tc, ok := g.typeConfigFor(stName); ok && tc.Receiver != "" {
+		return tc.Receiver
+	}
+	return strings.ToLower(stName[0:1])
+}
+
+// lockConfigFor resolves the type's configured default lock group, used by
+// lockSelector when a struct has multiple locks and a field doesn't pick
+// one with lock=.
+func (g *Generator) lockConfigFor(stName string) string {
+	tc, _ := g.typeConfigFor(stName)
+	return tc.Lock
+}
+
+// prependHeader finishes a generated file by prepending the package clause
+// and an import block built from what was actually referenced. Earlier
+// versions wrote raw buffered bytes with no imports at all, which only
+// worked because every template stuck to identifiers already in scope on
+// the receiver; the ctx and atomic-func templates reference packages the
+// generated file itself must import.
+func (g *Generator) prependHeader(stName string, usesAtomicImport, usesCtx bool) {
+	var imports []string
+	if usesAtomicImport {
+		imports = append(imports, "sync/atomic")
+	}
+	if usesCtx {
+		imports = append(imports, "context", "time")
+	}
+
+	header := bytes.NewBufferString(fmt.Sprintf("package %s\n\n", g.pkg.name))
+	if len(imports) > 0 {
+		header.WriteString("import (\n")
+		for _, imp := range imports {
+			fmt.Fprintf(header, "\t%q\n", imp)
+		}
+		header.WriteString(")\n\n")
+	}
+	if body, ok := g.buf[stName]; ok {
+		header.Write(body.Bytes())
+	}
+	g.buf[stName] = header
+}
+
 // generate produces the String method for the named type.
 func (g *Generator) generate(typeName string) {
+	// Each call re-parses every file from scratch for typeName's tag name,
+	// so structInfos and structLocks must start empty here; otherwise a
+	// second configured type accumulates the first type's entries on top
+	// of its own (duplicate decls in the output, inflated lock counts).
+	structInfos = nil
+	structLocks = make(map[string][]LockInfo)
+
 	for _, file := range g.pkg.files {
 		// Set the state for this run of the walker.
 		file.typeName = typeName
 		// ast.Print(file.fileSet, file.file)
 		if file.file != nil {
-			structInfo, err := ParseStruct(file.file, file.fileSet, TagName)
+			structInfo, err := ParseStruct(file.file, file.fileSet, g.pkg, g.tagFor(typeName))
 			if err != nil {
 				log.Panic(err)
 			}
@@ -209,16 +419,68 @@ func (g *Generator) generate(typeName string) {
 				checkExported(stName, info)
 				continue
 			}
-			g.Printf(stName, "package %s\n", g.pkg.name)
-			g.Printf(stName, "\n")
+			receiver := g.receiverFor(stName)
+			defaultLock := g.lockConfigFor(stName)
 
+			usesAtomicImport := false
+			usesCtx := false
 			for _, field := range info {
+				var load func(recv, field string) string
+				var store func(recv, field, value string) string
+				var atomicOK bool
+				atomicType := field.Type
+				if field.Atomic {
+					var valueType string
+					var needsImport bool
+					load, store, valueType, needsImport, atomicOK = atomicAccessor(field.ResolvedType, g.pkg.types)
+					if !atomicOK {
+						log.Printf("field %q in struct %q requested atomic accessors but its type is ineligible (plain bool/pointer fields must be declared as atomic.Bool/atomic.Pointer[T] to qualify); falling back to locked accessors", field.Name, stName)
+					} else {
+						if valueType != "" {
+							atomicType = valueType
+						}
+						if needsImport {
+							usesAtomicImport = true
+						}
+					}
+				}
+				// Atomic accessors take precedence over ctx ones below, so
+				// only a field that really gets ctx accessors needs context.
+				if field.Ctx && !atomicOK {
+					usesCtx = true
+				}
+
+				var lock, lockKind string
+				if !atomicOK {
+					lock, lockKind = lockSelector(stName, field, defaultLock)
+				}
 				for _, access := range field.Tags {
 					switch access {
 					case SetTag:
-						g.Printf(stName, "%s\n", genSetter(stName, field.Name, field.Type))
+						switch {
+						case atomicOK:
+							g.Printf(stName, "%s\n", g.genAtomicSetter(stName, receiver, field.Name, atomicType, store))
+						case field.Ctx:
+							usesCtx = true
+							g.Printf(stName, "%s\n", g.genCtxSetter(stName, receiver, field.Name, field.Type, lock))
+						case field.TryLock:
+							g.Printf(stName, "%s\n", g.genTrySetter(stName, receiver, field.Name, field.Type, lock))
+						default:
+							g.Printf(stName, "%s\n", g.genSetter(stName, receiver, field.Name, field.Type, lock))
+						}
 					case GetTag:
-						g.Printf(stName, "%s\n", genGetter(stName, field.Name, field.Type))
+						switch {
+						case atomicOK:
+							g.Printf(stName, "%s\n", g.genAtomicGetter(stName, receiver, field.Name, atomicType, load))
+						case field.Ctx:
+							g.Printf(stName, "%s\n", g.genCtxGetter(stName, receiver, field.Name, field.Type, lock, lockKind))
+						case field.TryLock:
+							g.Printf(stName, "%s\n", g.genTryGetter(stName, receiver, field.Name, field.Type, lock, lockKind))
+						case field.Copy || copyEligible(field.ResolvedType):
+							g.Printf(stName, "%s\n", g.genCopyGetter(stName, receiver, field.Name, field.Type, lock, lockKind, field.ResolvedType, g.pkg.types))
+						default:
+							g.Printf(stName, "%s\n", g.genGetter(stName, receiver, field.Name, field.Type, lock, lockKind))
+						}
 					case SkipTag:
 						continue
 					default:
@@ -227,6 +489,13 @@ func (g *Generator) generate(typeName string) {
 				}
 
 			}
+			if usesCtx {
+				g.Printf(stName, "const linaLockPollBackoff = 2 * time.Millisecond\n\n")
+			}
+			if usesAtomicImport {
+				g.Printf(stName, "var _ = atomic.LoadInt64\n\n")
+			}
+			g.prependHeader(stName, usesAtomicImport, usesCtx)
 		}
 	}
 
@@ -236,12 +505,19 @@ type StructFieldInfo struct {
 	Name string
 	Type string
 	Tags []string
+	Lock string // requested lock group, from lina:"...,lock=name"; empty means "resolve the default"
+
+	Atomic       bool       // true when the field set lina:"...,atomic"
+	Copy         bool       // true when the field set lina:"...,copy"
+	TryLock      bool       // true when the field set lina:"...,trylock"
+	Ctx          bool       // true when the field set lina:"...,ctx"
+	ResolvedType types.Type // field type as resolved by go/types; nil if type info wasn't available
 }
 type StructFieldInfoArr = []StructFieldInfo
 
 var embed bool
 
-func ParseStruct(file *ast.File, fileSet *token.FileSet, tagName string) (structMap map[string]StructFieldInfoArr, err error) {
+func ParseStruct(file *ast.File, fileSet *token.FileSet, pkg *Package, tagName string) (structMap map[string]StructFieldInfoArr, err error) {
 	structMap = make(map[string]StructFieldInfoArr)
 
 	var collectStructs func(ast.Node) bool
@@ -275,12 +551,16 @@ func ParseStruct(file *ast.File, fileSet *token.FileSet, tagName string) (struct
 		fileInfos := make([]StructFieldInfo, 0)
 
 		for _, field := range s.Fields.List {
-			if len(field.Names) == 0 {
-				if t, ok := field.Type.(*ast.SelectorExpr); ok && t.Sel.Name == "RWMutex" {
-					continue
-				} else {
-					log.Fatalf("only sync.RWMutex can be embed")
+			if kind, isLock := lockKind(field.Type); isLock {
+				var lockName string
+				if len(field.Names) != 0 {
+					lockName = field.Names[0].Name
 				}
+				structLocks[structName] = append(structLocks[structName], LockInfo{Name: lockName, Kind: kind})
+				continue
+			}
+			if len(field.Names) == 0 {
+				log.Fatalf("only sync.Mutex or sync.RWMutex can be embedded")
 			}
 
 			name := field.Names[0].Name
@@ -302,6 +582,9 @@ func ParseStruct(file *ast.File, fileSet *token.FileSet, tagName string) (struct
 				return true
 			}
 			info.Type = typeNameBuf.String()
+			if pkg != nil && pkg.info != nil {
+				info.ResolvedType = pkg.info.TypeOf(field.Type)
+			}
 			if t, ok := field.Type.(*ast.StructType); ok {
 				embed = true
 				collectStructs(t)
@@ -320,7 +603,7 @@ func ParseStruct(file *ast.File, fileSet *token.FileSet, tagName string) (struct
 					info.Tags = []string{GetTag, SetTag}
 				} else {
 					access.Options = append(access.Options, access.Name)
-					info.Tags = access.Options
+					info.Tags, info.Lock, info.Atomic, info.Copy, info.TryLock, info.Ctx = parseTagOptions(access.Options)
 				}
 			} else if !token.IsExported(name) {
 				info.Tags = []string{GetTag, SetTag}
@@ -336,25 +619,319 @@ func ParseStruct(file *ast.File, fileSet *token.FileSet, tagName string) (struct
 	return structMap, nil
 }
 
-func genGetter(structName, fieldName, typeName string) string {
-	return genFunc(getterTmpl, structName, fieldName, typeName, "")
+// lockKind reports whether expr names sync.Mutex or sync.RWMutex, either as
+// an anonymously embedded field or a named one (e.g. `cfgLock sync.RWMutex`).
+func lockKind(expr ast.Expr) (kind string, ok bool) {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok || pkg.Name != "sync" {
+		return "", false
+	}
+	switch sel.Sel.Name {
+	case "Mutex", "RWMutex":
+		return sel.Sel.Name, true
+	default:
+		return "", false
+	}
+}
+
+// lockOptionPrefix is the lina tag option that pins a field to a named lock
+// group, e.g. lina:"r,w,lock=cfgLock".
+const lockOptionPrefix = "lock="
+
+// atomicOption is the lina tag option that requests lock-free accessors,
+// e.g. lina:"r,w,atomic".
+const atomicOption = "atomic"
+
+// copyOption is the lina tag option that requests a deep-copying getter,
+// e.g. lina:"r,copy". Slice, map, and pointer-to-struct fields get this
+// behavior by default (see copyEligible), so the option mainly exists to
+// document intent and to let a future field type opt in explicitly.
+const copyOption = "copy"
+
+// tryLockOption is the lina tag option that generates a non-blocking
+// accessor pair returning an extra bool, e.g. lina:"r,w,trylock".
+const tryLockOption = "trylock"
+
+// ctxOption is the lina tag option that generates a context.Context-aware
+// accessor pair that backs off until the lock is acquired or ctx is done,
+// e.g. lina:"r,w,ctx".
+const ctxOption = "ctx"
+
+// parseTagOptions splits a lina tag's options into the remaining access tags
+// (r/w/skip) and the recognized modifiers: a "lock=name" group and the
+// "atomic"/"copy"/"trylock"/"ctx" flags.
+func parseTagOptions(options []string) (tags []string, lock string, atomic, cpy, tryLock, ctx bool) {
+	for _, opt := range options {
+		switch {
+		case strings.HasPrefix(opt, lockOptionPrefix):
+			lock = strings.TrimPrefix(opt, lockOptionPrefix)
+		case opt == atomicOption:
+			atomic = true
+		case opt == copyOption:
+			cpy = true
+		case opt == tryLockOption:
+			tryLock = true
+		case opt == ctxOption:
+			ctx = true
+		default:
+			tags = append(tags, opt)
+		}
+	}
+	return tags, lock, atomic, cpy, tryLock, ctx
+}
+
+// copyEligible reports whether t gets a deep-copying getter by default: a
+// slice, a map, or a pointer to a struct. These are exactly the shapes where
+// returning the raw field under RLock lets a caller mutate shared state
+// after RUnlock.
+func copyEligible(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	case *types.Pointer:
+		_, isStruct := u.Elem().Underlying().(*types.Struct)
+		return isStruct
+	}
+	return false
+}
+
+// typeString renders t as Go source, qualifying identifiers from other
+// packages but dropping the qualifier for pkg itself, matching how the
+// field's declared type is printed elsewhere in this file.
+func typeString(t types.Type, pkg *types.Package) string {
+	return types.TypeString(t, types.RelativeTo(pkg))
+}
+
+// copyExpr returns a Go expression that deep-copies src (itself an
+// expression, e.g. a range variable) of type t. Composite element types
+// recurse so a map of slices or a slice of pointers-to-struct copies down
+// through every slice, map and pointer-to-struct level. A pointed-to struct
+// is copied one level deep (its own fields are assigned as is), a nil
+// pointer stays nil, and anything else, including a pointer to a
+// non-struct, is returned unchanged, so whatever it references stays shared.
+func copyExpr(t types.Type, src string, pkg *types.Package) string {
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		elem := u.Elem()
+		if !copyEligible(elem) {
+			return fmt.Sprintf("append(%s(nil), %s...)", typeString(t, pkg), src)
+		}
+		return fmt.Sprintf("func() %s { out := make(%s, len(%s)); for i, v := range %s { out[i] = %s }; return out }()",
+			typeString(t, pkg), typeString(t, pkg), src, src, copyExpr(elem, "v", pkg))
+	case *types.Map:
+		elem := u.Elem()
+		valueExpr := "v"
+		if copyEligible(elem) {
+			valueExpr = copyExpr(elem, "v", pkg)
+		}
+		return fmt.Sprintf("func() %s { out := make(%s, len(%s)); for k, v := range %s { out[k] = %s }; return out }()",
+			typeString(t, pkg), typeString(t, pkg), src, src, valueExpr)
+	case *types.Pointer:
+		if _, isStruct := u.Elem().Underlying().(*types.Struct); isStruct {
+			return fmt.Sprintf("func() %s { if %s == nil { return nil }; cp := *%s; return &cp }()", typeString(t, pkg), src, src)
+		}
+	}
+	return src
+}
+
+// deepCopyBody renders the full getter body for a copy-eligible field: the
+// field read plus the copy expression that protects the caller from
+// mutating state still reachable from the struct.
+func deepCopyBody(recv, field string, t types.Type, pkg *types.Package) string {
+	return fmt.Sprintf("return %s", copyExpr(t, fmt.Sprintf("%s.%s", recv, field), pkg))
 }
-func genSetter(structName, fieldName, typeName string) string {
-	return genFunc(setterTmpl, structName, fieldName, typeName, "")
+
+// atomicAccessor returns the Load/Store expression builders to use for t
+// under the "atomic" tag option, or ok=false if t isn't eligible and the
+// field must fall back to the mutex-guarded template. Eligible types are the
+// sync/atomic wrapper types (Bool, Int32, Int64, Uint32, Uint64, Uintptr,
+// Value, Pointer[T]), accessed through their own Load/Store methods, and the
+// plain integer kinds (int32, int64, uint32, uint64, uintptr), accessed via
+// the matching atomic.LoadXxx/StoreXxx pair on the field's address. Classification
+// is done from the resolved types.Type, not the printed AST, so a defined
+// alias such as `type Counter = int64` is still recognized.
+//
+// valueType is the Go source for the accessor's parameter/return type. For
+// the wrapper-type branch this is deliberately NOT the field's own declared
+// type (e.g. "atomic.Bool") - Load/Store carry the underlying value, which
+// for Value is "any" and for Pointer[T] is "*T" - so it's derived from the
+// Load method's actual signature via types.Info rather than guessed from
+// the wrapper's name. It's empty for the func-style integer branch, since
+// the caller's already-resolved field type is the right type there.
+//
+// needsImport reports whether the generated code needs to import
+// "sync/atomic": true for the func-style branch (it calls
+// atomic.LoadXxx/StoreXxx directly) and also true for the wrapper-type
+// branch, whose compile-time assert (see generate) references
+// atomic.LoadInt64 regardless of which wrapper type triggered it.
+//
+// A plain bool or pointer/interface field is NOT eligible: there is no
+// atomic.LoadBool, and getting atomic.Bool/atomic.Pointer[T] semantics for
+// an already-declared plain field would mean rewriting the struct's field
+// type, which lina never does - it only ever adds methods. To get atomic
+// accessors for a bool or pointer, declare the field itself as
+// atomic.Bool / atomic.Pointer[T] and tag it "atomic"; it will hit the
+// sync/atomic wrapper branch above.
+func atomicAccessor(t types.Type, pkg *types.Package) (load func(recv, field string) string, store func(recv, field, value string) string, valueType string, needsImport, ok bool) {
+	if t == nil {
+		return nil, nil, "", false, false
+	}
+	if named, isNamed := t.(*types.Named); isNamed {
+		if obj := named.Obj(); obj != nil && obj.Pkg() != nil && obj.Pkg().Path() == "sync/atomic" {
+			switch {
+			case obj.Name() == "Bool", obj.Name() == "Int32", obj.Name() == "Int64",
+				obj.Name() == "Uint32", obj.Name() == "Uint64", obj.Name() == "Uintptr",
+				obj.Name() == "Value", strings.HasPrefix(obj.Name(), "Pointer"):
+				vt, ok := atomicLoadValueType(named)
+				if !ok {
+					return nil, nil, "", false, false
+				}
+				load, store := atomicMethodAccessor()
+				return load, store, typeString(vt, pkg), true, true
+			}
+		}
+		return nil, nil, "", false, false
+	}
+	if basic, isBasic := t.Underlying().(*types.Basic); isBasic {
+		switch basic.Kind() {
+		case types.Int32, types.Int64, types.Uint32, types.Uint64, types.Uintptr:
+			load, store := atomicFuncAccessor(basicKindSuffix(basic.Kind()))
+			return load, store, "", true, true
+		}
+	}
+	return nil, nil, "", false, false
+}
+
+// atomicLoadValueType returns the result type of named's Load() method -
+// the value Load/Store actually move - found via its real method set
+// rather than assumed from the wrapper's name, so Value (any) and
+// Pointer[T] (*T) come out right alongside Bool/Int64/etc.
+func atomicLoadValueType(named *types.Named) (types.Type, bool) {
+	mset := types.NewMethodSet(types.NewPointer(named))
+	for i := 0; i < mset.Len(); i++ {
+		obj := mset.At(i).Obj()
+		if obj.Name() != "Load" {
+			continue
+		}
+		sig, isSig := obj.Type().(*types.Signature)
+		if !isSig || sig.Results().Len() != 1 {
+			return nil, false
+		}
+		return sig.Results().At(0).Type(), true
+	}
+	return nil, false
+}
+
+// basicKindSuffix maps a types.BasicKind to the sync/atomic function suffix
+// used for the matching LoadXxx/StoreXxx pair.
+func basicKindSuffix(kind types.BasicKind) string {
+	switch kind {
+	case types.Int32:
+		return "Int32"
+	case types.Int64:
+		return "Int64"
+	case types.Uint32:
+		return "Uint32"
+	case types.Uint64:
+		return "Uint64"
+	case types.Uintptr:
+		return "Uintptr"
+	default:
+		return ""
+	}
+}
+
+// atomicMethodAccessor is used for fields already declared as one of the
+// sync/atomic wrapper types: their own Load/Store methods are lock-free.
+func atomicMethodAccessor() (func(recv, field string) string, func(recv, field, value string) string) {
+	load := func(recv, field string) string { return fmt.Sprintf("%s.%s.Load()", recv, field) }
+	store := func(recv, field, value string) string { return fmt.Sprintf("%s.%s.Store(%s)", recv, field, value) }
+	return load, store
 }
 
-func genFunc(funcName, structName, fieldName, typeName, lockName string) string {
+// atomicFuncAccessor is used for plain integer fields, operating on the
+// field's address through the package-level atomic.LoadXxx/StoreXxx pair.
+func atomicFuncAccessor(suffix string) (func(recv, field string) string, func(recv, field, value string) string) {
+	load := func(recv, field string) string { return fmt.Sprintf("atomic.Load%s(&%s.%s)", suffix, recv, field) }
+	store := func(recv, field, value string) string {
+		return fmt.Sprintf("atomic.Store%s(&%s.%s, %s)", suffix, recv, field, value)
+	}
+	return load, store
+}
+
+func (g *Generator) genGetter(structName, receiver, fieldName, typeName, lock, lockKind string) string {
+	return g.genFunc(getterTmpl, structName, receiver, fieldName, typeName, lock, lockKind, "", "", "")
+}
+func (g *Generator) genSetter(structName, receiver, fieldName, typeName, lock string) string {
+	return g.genFunc(setterTmpl, structName, receiver, fieldName, typeName, lock, "", "", "", "")
+}
+
+// genAtomicGetter and genAtomicSetter render the lock-free templates for a
+// field that opted into lina:"...,atomic" and resolved to an eligible type.
+func (g *Generator) genAtomicGetter(structName, receiver, fieldName, typeName string, load func(recv, field string) string) string {
+	return g.genFunc("getter_atomic", structName, receiver, fieldName, typeName, "", "", load(receiver, fieldName), "", "")
+}
+func (g *Generator) genAtomicSetter(structName, receiver, fieldName, typeName string, store func(recv, field, value string) string) string {
+	return g.genFunc("setter_atomic", structName, receiver, fieldName, typeName, "", "", "", store(receiver, fieldName, fieldName), "")
+}
+
+// genCopyGetter renders the getter_copy template for a slice/map/pointer
+// field, still read-lock-guarded but returning a deep copy instead of the
+// raw field value.
+func (g *Generator) genCopyGetter(structName, receiver, fieldName, typeName string, lock, lockKind string, resolvedType types.Type, pkg *types.Package) string {
+	return g.genFunc("getter_copy", structName, receiver, fieldName, typeName, lock, lockKind, "", "", deepCopyBody(receiver, fieldName, resolvedType, pkg))
+}
+
+// genTryGetter and genTrySetter render the non-blocking accessor pair for a
+// field tagged lina:"...,trylock".
+func (g *Generator) genTryGetter(structName, receiver, fieldName, typeName, lock, lockKind string) string {
+	return g.genFunc("getter_try", structName, receiver, fieldName, typeName, lock, lockKind, "", "", "")
+}
+func (g *Generator) genTrySetter(structName, receiver, fieldName, typeName, lock string) string {
+	return g.genFunc("setter_try", structName, receiver, fieldName, typeName, lock, "", "", "", "")
+}
+
+// genCtxGetter and genCtxSetter render the context.Context-aware accessor
+// pair for a field tagged lina:"...,ctx".
+func (g *Generator) genCtxGetter(structName, receiver, fieldName, typeName, lock, lockKind string) string {
+	return g.genFunc("getter_ctx", structName, receiver, fieldName, typeName, lock, lockKind, "", "", "")
+}
+func (g *Generator) genCtxSetter(structName, receiver, fieldName, typeName, lock string) string {
+	return g.genFunc("setter_ctx", structName, receiver, fieldName, typeName, lock, "", "", "", "")
+}
+
+func (g *Generator) genFunc(funcName, structName, receiver, fieldName, typeName, lockName, lockKind, atomicLoad, atomicStore, copyBody string) string {
+	var tmplText string
+	if g.cfg != nil {
+		tmplText = g.cfg.template(funcName, defaultTemplates[funcName])
+	} else {
+		tmplText = defaultTemplates[funcName]
+	}
 	t := template.New(funcName)
-	t = template.Must(t.Parse(tmpl[funcName]))
+	t = template.Must(t.Parse(tmplText))
 	res := bytes.NewBufferString("")
 	upperName := fmt.Sprintf("%s%s", strings.ToUpper(fieldName[0:1]), fieldName[1:])
+	rlock, runlock, tryrlock := readLockMethods(lockKind)
 	err := t.Execute(res, map[string]string{
-		"Receiver":   strings.ToLower(structName[0:1]),
-		"Struct":     structName,
-		"Field":      fieldName,
-		"Type":       typeName,
-		"UpperField": upperName,
-		"Lock":       lockName,
+		"Receiver":       receiver,
+		"Struct":         structName,
+		"Field":          fieldName,
+		"Type":           typeName,
+		"UpperField":     upperName,
+		"Lock":           lockName,
+		"RLockMethod":    rlock,
+		"RUnlockMethod":  runlock,
+		"TryRLockMethod": tryrlock,
+		"AtomicLoad":     atomicLoad,
+		"AtomicStore":    atomicStore,
+		"CopyBody":       copyBody,
 	})
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,22 @@
+package main
+
+// Plugin lets downstream users extend lina without forking it: mutate the
+// loaded Config before generation starts (e.g. inject a type, rewrite a
+// template), and/or emit extra code once generation finishes (metrics
+// hooks, event-bus emission on setters, ...).
+type Plugin interface {
+	Name() string
+	MutateConfig(*Config) error
+	GenerateCode(*Generator) error
+}
+
+// plugins holds every Plugin registered via RegisterPlugin. A plugin
+// typically registers itself from an init() in its own file, the way
+// database/sql drivers register themselves.
+var plugins []Plugin
+
+// RegisterPlugin adds p to the set run by main. Called from a plugin's
+// init().
+func RegisterPlugin(p Plugin) {
+	plugins = append(plugins, p)
+}